@@ -0,0 +1,62 @@
+package sharedaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const cloudEventSpecVersion = "1.0"
+
+// NOTE: wiring `cf logs --format=cloudevents-json` (and `--recent
+// --format=cloudevents-json`) onto these helpers, plus an integration test
+// analogous to integration/shared/isolated/logs_command_test.go that
+// validates the output against a CloudEvents schema, is tracked as a
+// follow-up rather than done here: this chunk of the tree has no
+// command/v7 package (no `cf logs` command source at all) for a --format
+// flag to be added to. ToCloudEvent/MarshalCloudEventJSON below are the
+// rendering half of that follow-up, ready for a logs command to call.
+
+// CloudEvent is a LogMessage re-shaped into a CloudEvents 1.0 JSON envelope
+// (https://github.com/cloudevents/spec), so CLI log output can be piped
+// straight into any CloudEvents-consuming sink (Knative, Argo Events,
+// Fluent Bit's cloudevents plugin) without a shim.
+type CloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Subject         string `json:"subject"`
+	Data            string `json:"data"`
+}
+
+// ToCloudEvent converts the receiver into its CloudEvents representation.
+// appGUID is needed because LogMessage itself only knows about the
+// source instance, not which app it belongs to.
+func (log LogMessage) ToCloudEvent(appGUID string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     cloudEventSpecVersion,
+		ID:              fmt.Sprintf("%d-%s", log.timestamp.UnixNano(), log.sourceInstance),
+		Source:          fmt.Sprintf("//cloudfoundry.org/apps/%s/%s", appGUID, log.sourceInstance),
+		Type:            cloudEventType(log.messageType),
+		Time:            log.timestamp.Format(time.RFC3339Nano),
+		DataContentType: "text/plain",
+		Subject:         log.sourceType,
+		Data:            log.message,
+	}
+}
+
+func cloudEventType(messageType string) string {
+	if messageType == "ERR" {
+		return "org.cloudfoundry.log.err"
+	}
+	return "org.cloudfoundry.log.out"
+}
+
+// MarshalCloudEventJSON renders log as a single line of CloudEvents JSON,
+// one envelope per line.
+func (log LogMessage) MarshalCloudEventJSON(appGUID string) ([]byte, error) {
+	return json.Marshal(log.ToCloudEvent(appGUID))
+}
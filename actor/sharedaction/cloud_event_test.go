@@ -0,0 +1,60 @@
+package sharedaction_test
+
+import (
+	"time"
+
+	. "code.cloudfoundry.org/cli/actor/sharedaction"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CloudEvent", func() {
+	var (
+		logMessage LogMessage
+		appGUID    string
+	)
+
+	BeforeEach(func() {
+		appGUID = "some-app-guid"
+		logMessage = *NewLogMessage(
+			"hello from log-cache",
+			"OUT",
+			time.Date(2020, 2, 11, 18, 10, 6, 0, time.UTC),
+			"APP/PROC/WEB",
+			"0",
+		)
+	})
+
+	Describe("ToCloudEvent", func() {
+		It("maps the log message onto a CloudEvents 1.0 envelope", func() {
+			event := logMessage.ToCloudEvent(appGUID)
+
+			Expect(event.SpecVersion).To(Equal("1.0"))
+			Expect(event.Source).To(Equal("//cloudfoundry.org/apps/some-app-guid/0"))
+			Expect(event.Type).To(Equal("org.cloudfoundry.log.out"))
+			Expect(event.Subject).To(Equal("APP/PROC/WEB"))
+			Expect(event.DataContentType).To(Equal("text/plain"))
+			Expect(event.Data).To(Equal("hello from log-cache"))
+		})
+
+		When("the message is from stderr", func() {
+			BeforeEach(func() {
+				logMessage = *NewLogMessage("oops", "ERR", time.Now(), "APP/PROC/WEB", "0")
+			})
+
+			It("uses the .err event type", func() {
+				Expect(logMessage.ToCloudEvent(appGUID).Type).To(Equal("org.cloudfoundry.log.err"))
+			})
+		})
+	})
+
+	Describe("MarshalCloudEventJSON", func() {
+		It("renders the envelope as a single line of JSON", func() {
+			raw, err := logMessage.MarshalCloudEventJSON(appGUID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(raw)).To(ContainSubstring(`"specversion":"1.0"`))
+			Expect(string(raw)).To(ContainSubstring(`"data":"hello from log-cache"`))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package sharedaction
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Exported for log_stream_test.go, which lives in sharedaction_test and
+// needs to exercise the same unexported channels pollLogCache selects on.
+func ExportDeadlineChan(s *LogStream) <-chan struct{} {
+	return s.deadlineChan()
+}
+
+func ExportReadDeadlineChan(s *LogStream) <-chan struct{} {
+	return s.readDeadlineChan()
+}
+
+// SetLogCachePollingInterval overrides the poll loop's idle wait for a test
+// and returns a func that restores the previous value. Both the override
+// and the restore go through the same atomic logCachePollingIntervalNS that
+// pollLogCache reads, so this is safe to call while a previous test's
+// pollLogCache goroutine is still winding down in the background.
+func SetLogCachePollingInterval(d time.Duration) (restore func()) {
+	previous := atomic.SwapInt64(&logCachePollingIntervalNS, int64(d))
+	return func() { atomic.StoreInt64(&logCachePollingIntervalNS, previous) }
+}
@@ -0,0 +1,169 @@
+package sharedaction
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DedupeOptions configures DedupingLogCacheClient.
+type DedupeOptions struct {
+	// Window bounds how long a (timestamp, source_instance, payload hash)
+	// key is remembered after it's first seen: once the newest envelope
+	// processed is Window or more past a remembered key's own timestamp,
+	// that key is forgotten. This is a genuinely time-bounded
+	// window - "remember keys from the last Window" - not a fixed-size
+	// LRU, so it stays correct regardless of how many envelopes arrive
+	// within it. Window should be comfortably larger than the lookback
+	// GetStreamingLogs refetches with (one second) so a re-fetched
+	// envelope is still recognized as a duplicate.
+	Window time.Duration
+
+	// WarnOnGap controls whether a synthetic ERR LogMessage is emitted when
+	// the next batch's earliest envelope arrives more than a nanosecond
+	// after the last envelope we saw - a sign log-cache truncated
+	// envelopes we hadn't been delivered yet. `cf logs` wants this; a
+	// caller such as `cf push` or `cf start` can set it to false so a busy
+	// deploy doesn't spam a warning about logs nobody's watching.
+	WarnOnGap bool
+}
+
+// DefaultDedupeOptions is what GetStreamingLogs uses when the caller
+// doesn't supply its own options.
+func DefaultDedupeOptions() DedupeOptions {
+	return DedupeOptions{
+		Window:    2 * time.Second,
+		WarnOnGap: true,
+	}
+}
+
+type dedupeKey struct {
+	timestamp      int64
+	sourceInstance string
+	payloadHash    uint64
+}
+
+// DedupingLogCacheClient wraps a LogCacheClient to drop envelopes already
+// delivered on a previous poll. GetStreamingLogs refetches on every poll
+// with `start_time = latest - 1s` so that it never misses an envelope
+// log-cache hadn't indexed yet; the tradeoff is that the same envelope
+// comes back on every poll until it ages out of that 1s window, so
+// something downstream has to collapse the duplicates.
+type DedupingLogCacheClient struct {
+	LogCacheClient
+	opts DedupeOptions
+
+	mu         sync.Mutex
+	seen       map[dedupeKey]struct{}
+	order      []dedupeKey
+	newestSeen time.Time
+	lastSeen   time.Time
+	haveSeen   bool
+}
+
+// NewDedupingLogCacheClient wraps client with de-duplication and (per opts)
+// gap-detection behavior.
+func NewDedupingLogCacheClient(client LogCacheClient, opts DedupeOptions) *DedupingLogCacheClient {
+	return &DedupingLogCacheClient{
+		LogCacheClient: client,
+		opts:           opts,
+		seen:           make(map[dedupeKey]struct{}),
+	}
+}
+
+func (client *DedupingLogCacheClient) Read(ctx context.Context, sourceID string, start time.Time, options ...interface{}) ([]*LogMessage, error) {
+	envelopes, err := client.LogCacheClient.Read(ctx, sourceID, start, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	var earliestNew time.Time
+	fresh := make([]*LogMessage, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		key := dedupeKey{
+			timestamp:      envelope.Timestamp().UnixNano(),
+			sourceInstance: envelope.SourceInstance(),
+			payloadHash:    hashPayload(envelope.Message()),
+		}
+
+		if _, ok := client.seen[key]; ok {
+			continue
+		}
+
+		client.remember(key, envelope.Timestamp())
+		fresh = append(fresh, envelope)
+
+		if earliestNew.IsZero() || envelope.Timestamp().Before(earliestNew) {
+			earliestNew = envelope.Timestamp()
+		}
+	}
+
+	previousLastSeen, hadSeen := client.lastSeen, client.haveSeen
+	for _, envelope := range fresh {
+		if envelope.Timestamp().After(client.lastSeen) {
+			client.lastSeen = envelope.Timestamp()
+			client.haveSeen = true
+		}
+	}
+
+	if client.opts.WarnOnGap && hadSeen && !earliestNew.IsZero() && earliestNew.After(previousLastSeen.Add(time.Nanosecond)) {
+		fresh = append([]*LogMessage{gapWarning(sourceID, earliestNew.Sub(previousLastSeen))}, fresh...)
+	}
+
+	return fresh, nil
+}
+
+func (client *DedupingLogCacheClient) remember(key dedupeKey, timestamp time.Time) {
+	client.seen[key] = struct{}{}
+	client.order = append(client.order, key)
+
+	if timestamp.After(client.newestSeen) {
+		client.newestSeen = timestamp
+	}
+	client.evictOlderThan(client.newestSeen.Add(-client.opts.Window))
+}
+
+// evictOlderThan forgets every remembered key whose own timestamp is at or
+// before cutoff. client.order holds keys oldest-first (envelopes dedupe in
+// the order GetStreamingLogs re-polls them), so this only ever has to
+// trim off the front.
+func (client *DedupingLogCacheClient) evictOlderThan(cutoff time.Time) {
+	if client.opts.Window <= 0 {
+		return
+	}
+
+	for len(client.order) > 0 {
+		oldest := client.order[0]
+		if time.Unix(0, oldest.timestamp).After(cutoff) {
+			break
+		}
+		client.order = client.order[1:]
+		delete(client.seen, oldest)
+	}
+}
+
+// gapWarning cannot know exactly how many messages log-cache dropped -
+// log-cache's API has no sequence number or drop counter to count against,
+// only the timestamps of the envelopes we did receive - so instead of
+// fabricating a message count it reports the size of the gap itself, which
+// is the most precise thing we actually know.
+func gapWarning(sourceID string, gap time.Duration) *LogMessage {
+	return NewLogMessage(
+		fmt.Sprintf("cf: dropped log messages from source %s due to log-cache truncation (gap of %s)", sourceID, gap),
+		"ERR",
+		time.Now(),
+		StagingLog,
+		"",
+	)
+}
+
+func hashPayload(payload string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(payload))
+	return h.Sum64()
+}
@@ -0,0 +1,130 @@
+package sharedaction_test
+
+import (
+	"context"
+	"time"
+
+	. "code.cloudfoundry.org/cli/actor/sharedaction"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeLogCacheClient struct {
+	batches [][]*LogMessage
+	calls   int
+}
+
+func (f *fakeLogCacheClient) Read(ctx context.Context, sourceID string, start time.Time, opts ...interface{}) ([]*LogMessage, error) {
+	if f.calls >= len(f.batches) {
+		return nil, nil
+	}
+	batch := f.batches[f.calls]
+	f.calls++
+	return batch, nil
+}
+
+var _ = Describe("DedupingLogCacheClient", func() {
+	var (
+		fakeClient *fakeLogCacheClient
+		t0         time.Time
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2020, 2, 11, 18, 10, 6, 0, time.UTC)
+		fakeClient = &fakeLogCacheClient{}
+	})
+
+	When("the same envelope is returned on consecutive polls", func() {
+		BeforeEach(func() {
+			envelope := NewLogMessage("hello", "OUT", t0, "APP/PROC/WEB", "0")
+			fakeClient.batches = [][]*LogMessage{
+				{envelope},
+				{envelope},
+			}
+		})
+
+		It("only delivers it once", func() {
+			client := NewDedupingLogCacheClient(fakeClient, DefaultDedupeOptions())
+
+			first, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first).To(HaveLen(1))
+
+			second, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(BeEmpty())
+		})
+	})
+
+	When("the next batch starts after a gap and WarnOnGap is set", func() {
+		BeforeEach(func() {
+			fakeClient.batches = [][]*LogMessage{
+				{NewLogMessage("first", "OUT", t0, "APP/PROC/WEB", "0")},
+				{NewLogMessage("much later", "OUT", t0.Add(time.Hour), "APP/PROC/WEB", "0")},
+			}
+		})
+
+		It("prepends a synthetic ERR message about the gap", func() {
+			client := NewDedupingLogCacheClient(fakeClient, DedupeOptions{Window: time.Minute, WarnOnGap: true})
+
+			_, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(HaveLen(2))
+			Expect(second[0].Type()).To(Equal("ERR"))
+			Expect(second[0].Message()).To(ContainSubstring("log-cache truncation"))
+			Expect(second[1].Message()).To(Equal("much later"))
+		})
+	})
+
+	When("an envelope reappears after it's aged out of the window", func() {
+		BeforeEach(func() {
+			envelope := NewLogMessage("hello", "OUT", t0, "APP/PROC/WEB", "0")
+			fakeClient.batches = [][]*LogMessage{
+				{envelope},
+				{NewLogMessage("keep the window moving", "OUT", t0.Add(time.Hour), "APP/PROC/WEB", "0")},
+				{envelope},
+			}
+		})
+
+		It("delivers it again, since Window only remembers keys within the last N seconds", func() {
+			client := NewDedupingLogCacheClient(fakeClient, DedupeOptions{Window: time.Second, WarnOnGap: false})
+
+			first, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first).To(HaveLen(1))
+
+			_, err = client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+
+			third, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(third).To(HaveLen(1))
+			Expect(third[0].Message()).To(Equal("hello"))
+		})
+	})
+
+	When("WarnOnGap is false", func() {
+		BeforeEach(func() {
+			fakeClient.batches = [][]*LogMessage{
+				{NewLogMessage("first", "OUT", t0, "APP/PROC/WEB", "0")},
+				{NewLogMessage("much later", "OUT", t0.Add(time.Hour), "APP/PROC/WEB", "0")},
+			}
+		})
+
+		It("does not emit a gap warning", func() {
+			client := NewDedupingLogCacheClient(fakeClient, DedupeOptions{Window: time.Minute, WarnOnGap: false})
+
+			_, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := client.Read(context.Background(), "some-source", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(HaveLen(1))
+			Expect(second[0].Message()).To(Equal("much later"))
+		})
+	})
+})
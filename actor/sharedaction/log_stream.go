@@ -0,0 +1,134 @@
+package sharedaction
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned (and delivered on the streaming logs'
+// error channel) once a deadline set via LogStream.SetDeadline or
+// SetReadDeadline has passed.
+var ErrDeadlineExceeded = errors.New("sharedaction: log stream deadline exceeded")
+
+// LogStream controls an in-flight GetStreamingLogs call the same way
+// net.Conn controls a connection: callers can bound how long they're
+// willing to wait for the next envelope from log-cache with SetDeadline and
+// SetReadDeadline, and stop the underlying poll for good with Close.
+//
+// The deadline bookkeeping mirrors net's pipeDeadline (see gonet's
+// deadlineTimer): each deadline is a pointer to a *time.Timer plus a
+// cancellation channel, both guarded by a mutex, and resetting the
+// deadline replaces the timer atomically so there's no race between a
+// goroutine waiting on the channel and one calling Set*Deadline.
+type LogStream struct {
+	deadline     pipeDeadline
+	readDeadline pipeDeadline
+	cancel       context.CancelFunc
+}
+
+// NewLogStream wraps the cancel func for a GetStreamingLogs call.
+func NewLogStream(cancel context.CancelFunc) *LogStream {
+	return &LogStream{
+		deadline:     makePipeDeadline(),
+		readDeadline: makePipeDeadline(),
+		cancel:       cancel,
+	}
+}
+
+// SetDeadline sets the absolute time after which the stream gives up
+// waiting for new envelopes altogether, independent of read activity. A
+// zero value disables the deadline.
+func (s *LogStream) SetDeadline(t time.Time) {
+	s.deadline.set(t)
+}
+
+// SetReadDeadline sets the absolute time by which the *next* envelope must
+// arrive from log-cache. Unlike SetDeadline, commands are expected to push
+// this back out after every successful poll.
+func (s *LogStream) SetReadDeadline(t time.Time) {
+	s.readDeadline.set(t)
+}
+
+// Close stops the underlying log-cache poll. Any poll blocked waiting on an
+// envelope is interrupted and the message channel is closed; no error is
+// reported for a Close.
+func (s *LogStream) Close() error {
+	s.cancel()
+	return nil
+}
+
+// deadlineChan and readDeadlineChan return the channels pollLogCache
+// selects on directly - no merging goroutine, so a stream whose deadlines
+// are never set (the common case) costs nothing beyond the two channels
+// allocated by makePipeDeadline.
+func (s *LogStream) deadlineChan() <-chan struct{} {
+	return s.deadline.wait()
+}
+
+func (s *LogStream) readDeadlineChan() <-chan struct{} {
+	return s.readDeadline.wait()
+}
+
+// pipeDeadline is the same design as net.pipeDeadline: a timer and a
+// cancellation channel guarded by a mutex so the deadline can be reset
+// concurrently with a goroutine waiting on it.
+type pipeDeadline struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+func makePipeDeadline() pipeDeadline {
+	return pipeDeadline{cancel: make(chan struct{})}
+}
+
+// set sets the point in time when the deadline will time out. A zero time
+// means no deadline.
+func (d *pipeDeadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil && !d.timer.Stop() {
+		<-d.cancel // timer already fired; drain it
+	}
+	d.timer = nil
+
+	closed := isClosedChan(d.cancel)
+	if t.IsZero() {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		return
+	}
+
+	if dur := time.Until(t); dur > 0 {
+		if closed {
+			d.cancel = make(chan struct{})
+		}
+		cancel := d.cancel
+		d.timer = time.AfterFunc(dur, func() { close(cancel) })
+		return
+	}
+
+	// deadline already in the past
+	if !closed {
+		close(d.cancel)
+	}
+}
+
+func (d *pipeDeadline) wait() chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
+
+func isClosedChan(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,73 @@
+package sharedaction_test
+
+import (
+	"runtime"
+	"time"
+
+	. "code.cloudfoundry.org/cli/actor/sharedaction"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogStream", func() {
+	var (
+		stream   *LogStream
+		canceled bool
+	)
+
+	BeforeEach(func() {
+		canceled = false
+		stream = NewLogStream(func() { canceled = true })
+	})
+
+	Describe("Close", func() {
+		It("invokes the underlying cancel func", func() {
+			Expect(stream.Close()).To(Succeed())
+			Expect(canceled).To(BeTrue())
+		})
+	})
+
+	Describe("SetDeadline", func() {
+		When("the deadline is in the past", func() {
+			It("closes the deadline channel immediately", func() {
+				stream.SetDeadline(time.Now().Add(-time.Second))
+				Eventually(ExportDeadlineChan(stream)).Should(BeClosed())
+			})
+		})
+
+		When("the deadline is reset before it fires", func() {
+			It("the cleared deadline never closes the channel", func() {
+				stream.SetDeadline(time.Now().Add(time.Hour))
+				stream.SetDeadline(time.Time{})
+
+				Consistently(ExportDeadlineChan(stream), "50ms").ShouldNot(BeClosed())
+			})
+		})
+
+		When("the deadline channel is read repeatedly without ever being set", func() {
+			It("does not spawn a goroutine per read", func() {
+				runtime.GC()
+				before := runtime.NumGoroutine()
+
+				for i := 0; i < 200; i++ {
+					select {
+					case <-ExportDeadlineChan(stream):
+						Fail("deadline channel closed without a deadline ever being set")
+					default:
+					}
+				}
+
+				Eventually(func() int { return runtime.NumGoroutine() }).Should(BeNumerically("<=", before+2))
+			})
+		})
+	})
+
+	Describe("SetReadDeadline", func() {
+		It("closes independently of SetDeadline", func() {
+			stream.SetReadDeadline(time.Now().Add(-time.Second))
+			Eventually(ExportReadDeadlineChan(stream)).Should(BeClosed())
+			Consistently(ExportDeadlineChan(stream), "20ms").ShouldNot(BeClosed())
+		})
+	})
+})
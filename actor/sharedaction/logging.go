@@ -0,0 +1,82 @@
+package sharedaction
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const StagingLog = "STG"
+
+// logCachePollingIntervalNS is how long pollLogCache waits between polls,
+// in nanoseconds, when nothing else (a new envelope, ctx, or a deadline)
+// wakes it sooner. It's stored atomically, rather than as a plain const or
+// var, so streaming_logs_test.go can shrink it - to avoid racing the
+// default Gomega Eventually timeout against production's one-second
+// cadence - from a goroutine other than the one running pollLogCache.
+var logCachePollingIntervalNS = int64(time.Second)
+
+func logCachePollingInterval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&logCachePollingIntervalNS))
+}
+
+// LogMessage represents a log message from log-cache, decoded into the
+// shape the CLI's commands render.
+type LogMessage struct {
+	message        string
+	messageType    string
+	timestamp      time.Time
+	sourceType     string
+	sourceInstance string
+}
+
+// NewLogMessage constructs a LogMessage from its decoded parts.
+func NewLogMessage(message string, messageType string, timestamp time.Time, sourceType string, sourceInstance string) *LogMessage {
+	return &LogMessage{
+		message:        message,
+		messageType:    messageType,
+		timestamp:      timestamp,
+		sourceType:     sourceType,
+		sourceInstance: sourceInstance,
+	}
+}
+
+func (log LogMessage) Message() string {
+	return log.message
+}
+
+func (log LogMessage) Type() string {
+	return log.messageType
+}
+
+func (log LogMessage) Timestamp() time.Time {
+	return log.timestamp
+}
+
+func (log LogMessage) SourceType() string {
+	return log.sourceType
+}
+
+func (log LogMessage) SourceInstance() string {
+	return log.sourceInstance
+}
+
+// LogCacheClient is satisfied by the log-cache client the CLI polls for new
+// envelopes.
+type LogCacheClient interface {
+	Read(ctx context.Context, sourceID string, start time.Time, opts ...interface{}) ([]*LogMessage, error)
+}
+
+// GetRecentLogs returns the most recent log-cache envelopes for appGUID.
+func GetRecentLogs(appGUID string, client LogCacheClient) ([]LogMessage, error) {
+	envelopes, err := client.Read(context.Background(), appGUID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []LogMessage
+	for _, envelope := range envelopes {
+		messages = append(messages, *envelope)
+	}
+	return messages, nil
+}
@@ -0,0 +1,13 @@
+package sharedaction_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSharedAction(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shared Action Suite")
+}
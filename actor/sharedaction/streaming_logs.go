@@ -0,0 +1,148 @@
+package sharedaction
+
+import (
+	"context"
+	"time"
+)
+
+// GetStreamingLogs polls log-cache for new envelopes for appGUID and
+// delivers them on the returned LogMessage channel until the returned
+// LogStream is closed or a deadline set on it elapses.
+//
+// The LogStream takes the place of the bare context.CancelFunc this used to
+// return: it additionally lets callers set idle and per-message read
+// deadlines (SetDeadline, SetReadDeadline) so commands like `cf logs`, `cf
+// push`, and `cf start` can bound how long they'll wait for the next
+// envelope instead of blocking forever against an empty log-cache.
+//
+// client is wrapped in a DedupingLogCacheClient so that the overlapping
+// refetches described on DedupingLogCacheClient don't redeliver the same
+// envelope on every poll. opts is optional; pass it to control the dedupe
+// Window or to opt out of the gap warning - DefaultDedupeOptions() is used
+// otherwise.
+func GetStreamingLogs(appGUID string, client LogCacheClient, opts ...DedupeOptions) (<-chan LogMessage, <-chan error, *LogStream) {
+	dedupeOpts := DefaultDedupeOptions()
+	if len(opts) > 0 {
+		dedupeOpts = opts[0]
+	}
+	client = NewDedupingLogCacheClient(client, dedupeOpts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := NewLogStream(cancel)
+
+	messages := make(chan LogMessage, 100)
+	errs := make(chan error, 1)
+
+	go pollLogCache(ctx, appGUID, client, stream, messages, errs)
+
+	return messages, errs, stream
+}
+
+func pollLogCache(ctx context.Context, appGUID string, client LogCacheClient, stream *LogStream, messages chan<- LogMessage, errs chan<- error) {
+	defer close(messages)
+
+	start := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stream.deadlineChan():
+			reportDeadlineExceeded(errs)
+			return
+		case <-stream.readDeadlineChan():
+			reportDeadlineExceeded(errs)
+			return
+		default:
+		}
+
+		envelopes, err := readWithDeadline(ctx, appGUID, start, client, stream)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if deadlineFired(stream) {
+				reportDeadlineExceeded(errs)
+				return
+			}
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		for _, envelope := range envelopes {
+			select {
+			case messages <- *envelope:
+			case <-ctx.Done():
+				return
+			case <-stream.deadlineChan():
+				reportDeadlineExceeded(errs)
+				return
+			case <-stream.readDeadlineChan():
+				reportDeadlineExceeded(errs)
+				return
+			}
+			start = envelope.Timestamp().Add(-time.Second)
+		}
+
+		select {
+		case <-time.After(logCachePollingInterval()):
+		case <-ctx.Done():
+			return
+		case <-stream.deadlineChan():
+			reportDeadlineExceeded(errs)
+			return
+		case <-stream.readDeadlineChan():
+			reportDeadlineExceeded(errs)
+			return
+		}
+	}
+}
+
+func reportDeadlineExceeded(errs chan<- error) {
+	select {
+	case errs <- ErrDeadlineExceeded:
+	default:
+	}
+}
+
+// readWithDeadline calls client.Read with a context that's canceled the
+// moment stream's deadline or read deadline fires, not just before and
+// after the call - otherwise a deadline elapsing while Read is already
+// blocked waiting on log-cache would never interrupt it. The watcher
+// goroutine it starts always exits promptly: either a deadline fires, or
+// the deferred cancel runs as soon as Read returns, so no goroutine
+// outlives the Read call it was started for.
+func readWithDeadline(ctx context.Context, appGUID string, start time.Time, client LogCacheClient, stream *LogStream) ([]*LogMessage, error) {
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-readCtx.Done():
+		case <-stream.deadlineChan():
+			cancel()
+		case <-stream.readDeadlineChan():
+			cancel()
+		}
+	}()
+
+	return client.Read(readCtx, appGUID, start)
+}
+
+// deadlineFired reports whether stream's deadline or read deadline has
+// elapsed, without blocking. Call this right after a Read call returns an
+// error to tell an interrupted-by-deadline Read apart from a genuine
+// log-cache error.
+func deadlineFired(stream *LogStream) bool {
+	select {
+	case <-stream.deadlineChan():
+		return true
+	case <-stream.readDeadlineChan():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package sharedaction_test
+
+import (
+	"context"
+	"time"
+
+	. "code.cloudfoundry.org/cli/actor/sharedaction"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type blockingLogCacheClient struct {
+	reads chan []*LogMessage
+}
+
+func (c *blockingLogCacheClient) Read(ctx context.Context, sourceID string, start time.Time, opts ...interface{}) ([]*LogMessage, error) {
+	select {
+	case batch := <-c.reads:
+		return batch, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+var _ = Describe("GetStreamingLogs", func() {
+	var client *blockingLogCacheClient
+
+	BeforeEach(func() {
+		client = &blockingLogCacheClient{reads: make(chan []*LogMessage, 10)}
+	})
+
+	It("delivers envelopes as they arrive", func() {
+		messages, _, stream := GetStreamingLogs("some-app-guid", client)
+		defer stream.Close()
+
+		client.reads <- []*LogMessage{NewLogMessage("hello", "OUT", time.Now(), "APP/PROC/WEB", "0")}
+
+		Eventually(messages).Should(Receive(WithTransform(LogMessage.Message, Equal("hello"))))
+	})
+
+	When("the stream is closed", func() {
+		It("stops delivering and closes the message channel", func() {
+			messages, _, stream := GetStreamingLogs("some-app-guid", client)
+
+			Expect(stream.Close()).To(Succeed())
+
+			Eventually(messages).Should(BeClosed())
+		})
+	})
+
+	When("a deadline set on the stream elapses while a poll is already blocked on log-cache", func() {
+		It("interrupts the in-flight read and reports ErrDeadlineExceeded", func() {
+			// client.reads is never fed, so the poll underneath is already
+			// blocked on <-c.reads when the deadline fires; without
+			// threading the deadline into Read's context this blocks
+			// forever instead of returning.
+			messages, errs, stream := GetStreamingLogs("some-app-guid", client)
+			defer stream.Close()
+
+			stream.SetDeadline(time.Now().Add(10 * time.Millisecond))
+
+			Eventually(errs, "1s").Should(Receive(Equal(ErrDeadlineExceeded)))
+			Eventually(messages).Should(BeClosed())
+		})
+	})
+
+	When("no deadline is ever set", func() {
+		It("keeps polling and delivers every new batch", func() {
+			// The production polling interval (one second) is close enough
+			// to Gomega's default Eventually timeout that this spec would
+			// be racing them against each other; shrink it for the
+			// duration of this test instead.
+			restore := SetLogCachePollingInterval(5 * time.Millisecond)
+			defer restore()
+
+			messages, _, stream := GetStreamingLogs("some-app-guid", client)
+			defer stream.Close()
+
+			for i := 0; i < 5; i++ {
+				client.reads <- []*LogMessage{NewLogMessage("hi", "OUT", time.Now(), "APP/PROC/WEB", "0")}
+				Eventually(messages).Should(Receive())
+			}
+		})
+	})
+})
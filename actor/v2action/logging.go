@@ -3,6 +3,7 @@ package v2action
 import (
 	"context"
 	"errors"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -11,8 +12,25 @@ import (
 	"code.cloudfoundry.org/cli/actor/sharedaction"
 )
 
-func (actor Actor) GetStreamingLogs(appGUID string, client sharedaction.LogCacheClient) (<-chan sharedaction.LogMessage, <-chan error, context.CancelFunc) {
-	return sharedaction.GetStreamingLogs(appGUID, client)
+const (
+	// refreshSuccessJitter is the +/- window applied to the interval between
+	// successful refreshes so that concurrent CLI invocations against the
+	// same UAA don't all wake up and refresh at the same instant.
+	refreshSuccessJitter = 0.05
+
+	// refreshBackoffInitialInterval is how long we wait before retrying a
+	// failed refresh the first time.
+	refreshBackoffInitialInterval = 1 * time.Second
+
+	// refreshBackoffMaxInterval caps the exponential backoff between
+	// consecutive failed refreshes.
+	refreshBackoffMaxInterval = 1 * time.Minute
+
+	refreshBackoffMultiplier = 2
+)
+
+func (actor Actor) GetStreamingLogs(appGUID string, client sharedaction.LogCacheClient, opts ...sharedaction.DedupeOptions) (<-chan sharedaction.LogMessage, <-chan error, *sharedaction.LogStream) {
+	return sharedaction.GetStreamingLogs(appGUID, client, opts...)
 }
 
 func (actor Actor) GetRecentLogsForApplicationByNameAndSpace(appName string, spaceGUID string, client sharedaction.LogCacheClient) ([]sharedaction.LogMessage, Warnings, error) {
@@ -41,57 +59,130 @@ func (actor Actor) GetRecentLogsForApplicationByNameAndSpace(appName string, spa
 	return logMessages, allWarnings, nil
 }
 
-func (actor Actor) GetStreamingLogsForApplicationByNameAndSpace(appName string, spaceGUID string, client sharedaction.LogCacheClient) (<-chan sharedaction.LogMessage, <-chan error, context.CancelFunc, Warnings, error) {
+func (actor Actor) GetStreamingLogsForApplicationByNameAndSpace(appName string, spaceGUID string, client sharedaction.LogCacheClient, opts ...sharedaction.DedupeOptions) (<-chan sharedaction.LogMessage, <-chan error, *sharedaction.LogStream, Warnings, error) {
 	app, allWarnings, err := actor.GetApplicationByNameAndSpace(appName, spaceGUID)
 	if err != nil {
-		return nil, nil, func() {}, allWarnings, err
+		return nil, nil, nil, allWarnings, err
+	}
+
+	messages, logErrs, stream := actor.GetStreamingLogs(app.GUID, client, opts...)
+
+	return messages, logErrs, stream, allWarnings, err
+}
+
+// ScheduleTokenRefresh refreshes the access token at roughly 90% of its
+// remaining lifetime (plus or minus a small jitter window so that many CLI
+// invocations against the same UAA don't stampede it at once), and keeps
+// retrying with exponential backoff and jitter if a refresh fails. It never
+// panics: failures are reported on the returned error channel so callers
+// can decide how to react instead of crashing the CLI.
+//
+// The returned quit channel stops the refresh loop when closed or sent to.
+// The loop also stops when ctx is done, so callers can tie the refresh
+// lifetime to command-level cancellation without having to remember to
+// close the quit channel themselves. If tickerChan is non-nil it is used
+// in place of the actor's own timer, which is how tests control timing.
+func (actor Actor) ScheduleTokenRefresh(ctx context.Context, tickerChan <-chan time.Time) (chan bool, <-chan error, error) {
+	timeToRefresh, err := actor.refreshAndComputeNextInterval()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	messages, logErrs, stopStreaming := actor.GetStreamingLogs(app.GUID, client)
+	quitNowChannel := make(chan bool, 1)
+	errChannel := make(chan error, 1)
+
+	go refreshLoop(ctx, tickerChan, quitNowChannel, errChannel, timeToRefresh, actor.refreshAndComputeNextInterval)
 
-	return messages, logErrs, stopStreaming, allWarnings, err
+	return quitNowChannel, errChannel, nil
 }
 
-func (actor Actor) ScheduleTokenRefresh(tickerChan <-chan time.Time) (chan bool, error) {
+// refreshAndComputeNextInterval refreshes the access token once and returns
+// how long to wait before the next successful-path refresh.
+func (actor Actor) refreshAndComputeNextInterval() (time.Duration, error) {
 	accessTokenString, err := actor.RefreshAccessToken(actor.Config.RefreshToken())
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	accessTokenString = strings.TrimPrefix(accessTokenString, "bearer ")
 	token, err := jws.ParseJWT([]byte(accessTokenString))
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	var timeToRefresh time.Duration
 	expiration, ok := token.Claims().Expiration()
-	if ok {
-		expiresIn := time.Until(expiration)
-		timeToRefresh = expiresIn * 9 / 10
-	} else {
-		return nil, errors.New("Failed to get an expiry time from the current access token")
+	if !ok {
+		return 0, errors.New("Failed to get an expiry time from the current access token")
 	}
-	quitNowChannel := make(chan bool, 1)
 
-	go func() {
-		if tickerChan == nil {
-			ticker := time.NewTicker(timeToRefresh)
-			defer ticker.Stop()
-			tickerChan = ticker.C
-		}
-		for {
-			select {
-			case <-tickerChan:
-				_, err := actor.RefreshAccessToken(actor.Config.RefreshToken())
-				if err != nil {
-					panic(err)
-				}
-			case <-quitNowChannel:
-				return
+	expiresIn := time.Until(expiration)
+	return jitterDuration(expiresIn*9/10, refreshSuccessJitter), nil
+}
+
+// refreshLoop holds ScheduleTokenRefresh's backoff/timer/cancellation
+// logic. It takes the refresh step as a closure instead of an Actor method
+// so logging_test.go can drive it with a fake refresh function and a
+// supplied tickerChan instead of a real Actor/Config/UAA round trip.
+func refreshLoop(ctx context.Context, tickerChan <-chan time.Time, quitNowChannel chan bool, errChannel chan<- error, timeToRefresh time.Duration, refreshAndComputeNextInterval func() (time.Duration, error)) {
+	backoff := refreshBackoffInitialInterval
+
+	timer := time.NewTimer(timeToRefresh)
+	defer timer.Stop()
+
+	refreshChan := tickerChan
+	if refreshChan == nil {
+		refreshChan = timer.C
+	}
+
+	for {
+		select {
+		case <-refreshChan:
+			nextInterval, err := refreshAndComputeNextInterval()
+			if err != nil {
+				reportRefreshError(errChannel, err)
+
+				nextInterval = jitterDuration(backoff, refreshSuccessJitter)
+				backoff = nextBackoff(backoff)
+			} else {
+				backoff = refreshBackoffInitialInterval
+			}
+
+			if tickerChan == nil {
+				timer.Reset(nextInterval)
+				refreshChan = timer.C
 			}
+		case <-quitNowChannel:
+			return
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
+}
+
+// reportRefreshError sends err on errChannel without blocking the refresh
+// loop if nobody is listening.
+func reportRefreshError(errChannel chan<- error, err error) {
+	select {
+	case errChannel <- err:
+	default:
+	}
+}
 
-	return quitNowChannel, nil
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * refreshBackoffMultiplier
+	if next > refreshBackoffMaxInterval {
+		next = refreshBackoffMaxInterval
+	}
+	return next
+}
+
+// jitterDuration returns d adjusted by a random amount within +/- fraction
+// of d (e.g. fraction 0.05 for a +/-5% window).
+func jitterDuration(d time.Duration, fraction float64) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	spread := float64(d) * fraction
+	offset := (rand.Float64()*2 - 1) * spread
+	return d + time.Duration(offset)
 }
@@ -0,0 +1,131 @@
+package v2action
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// stubRefresher is a fake refreshAndComputeNextInterval: each call pops the
+// next scripted result, or repeats the last one once the script runs out.
+type stubRefresher struct {
+	mu      sync.Mutex
+	results []refreshResult
+	calls   int
+}
+
+type refreshResult struct {
+	interval time.Duration
+	err      error
+}
+
+func (s *stubRefresher) refresh() (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.calls
+	if i >= len(s.results) {
+		i = len(s.results) - 1
+	}
+	s.calls++
+	return s.results[i].interval, s.results[i].err
+}
+
+func (s *stubRefresher) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+var _ = Describe("refreshLoop", func() {
+	var (
+		tickerChan     chan time.Time
+		quitNowChannel chan bool
+		errChannel     chan error
+		ctx            context.Context
+		cancel         context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		tickerChan = make(chan time.Time)
+		quitNowChannel = make(chan bool, 1)
+		errChannel = make(chan error, 1)
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("ctx is canceled", func() {
+		It("exits the loop", func() {
+			refresher := &stubRefresher{results: []refreshResult{{interval: time.Minute}}}
+			done := make(chan struct{})
+
+			go func() {
+				refreshLoop(ctx, tickerChan, quitNowChannel, errChannel, time.Hour, refresher.refresh)
+				close(done)
+			}()
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	When("quitNowChannel is sent to", func() {
+		It("exits the loop", func() {
+			refresher := &stubRefresher{results: []refreshResult{{interval: time.Minute}}}
+			done := make(chan struct{})
+
+			go func() {
+				refreshLoop(ctx, tickerChan, quitNowChannel, errChannel, time.Hour, refresher.refresh)
+				close(done)
+			}()
+
+			quitNowChannel <- true
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	When("a refresh tick fails", func() {
+		It("reports the error and keeps looping instead of exiting", func() {
+			refreshErr := errors.New("refresh failed")
+			refresher := &stubRefresher{results: []refreshResult{
+				{err: refreshErr},
+				{interval: time.Minute},
+			}}
+
+			go refreshLoop(ctx, tickerChan, quitNowChannel, errChannel, time.Hour, refresher.refresh)
+			defer func() { quitNowChannel <- true }()
+
+			tickerChan <- time.Now()
+			Eventually(errChannel).Should(Receive(Equal(refreshErr)))
+
+			tickerChan <- time.Now()
+			Eventually(refresher.callCount).Should(Equal(2))
+		})
+	})
+
+	When("a refresh tick succeeds after a prior failure", func() {
+		It("keeps refreshing without sending anything further on errChannel", func() {
+			refresher := &stubRefresher{results: []refreshResult{
+				{err: errors.New("first failure")},
+				{interval: time.Minute},
+			}}
+
+			go refreshLoop(ctx, tickerChan, quitNowChannel, errChannel, time.Hour, refresher.refresh)
+			defer func() { quitNowChannel <- true }()
+
+			tickerChan <- time.Now()
+			Eventually(errChannel).Should(Receive())
+
+			tickerChan <- time.Now()
+			Eventually(refresher.callCount).Should(Equal(2))
+			Consistently(errChannel).ShouldNot(Receive())
+		})
+	})
+})
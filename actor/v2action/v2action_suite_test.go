@@ -0,0 +1,13 @@
+package v2action_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestV2Action(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "V2 Action Suite")
+}
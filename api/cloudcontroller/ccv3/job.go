@@ -0,0 +1,203 @@
+package ccv3
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/cli/api/cloudcontroller"
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3/internal"
+)
+
+// JobState is the lifecycle state of an asynchronous Cloud Controller job.
+type JobState string
+
+const (
+	JobStateProcessing JobState = "PROCESSING"
+	JobStateComplete   JobState = "COMPLETE"
+	JobStateFailed     JobState = "FAILED"
+)
+
+// JobErrorDetails is one of the errors Cloud Controller attaches to a
+// failed job.
+type JobErrorDetails struct {
+	Code   int    `json:"code"`
+	Detail string `json:"detail"`
+	Title  string `json:"title"`
+}
+
+// Job represents a Cloud Controller asynchronous job.
+type Job struct {
+	GUID   string            `json:"guid"`
+	State  JobState          `json:"state"`
+	Errors []JobErrorDetails `json:"errors"`
+}
+
+// GetJob fetches the current state of the job at url.
+func (client *Client) GetJob(url JobURL) (Job, Warnings, error) {
+	request, err := client.NewHTTPRequest(requestOptions{
+		RequestName: internal.GetJobRequest,
+		URIParams:   internal.Params{"job_guid": url.guid()},
+	})
+	if err != nil {
+		return Job{}, nil, err
+	}
+
+	var job Job
+	response := cloudcontroller.Response{DecodeJSONResponseInto: &job}
+	err = client.Connection.Make(request, &response)
+
+	return job, response.Warnings, err
+}
+
+// PollOptions configures Client.PollJob.
+type PollOptions struct {
+	// InitialInterval is how long to wait before the first re-check of the
+	// job. Defaults to 1 second.
+	InitialInterval time.Duration
+	// MaxInterval caps the exponential backoff between re-checks. Defaults
+	// to 30 seconds.
+	MaxInterval time.Duration
+	// MaxElapsedTime bounds the total time PollJob will wait before giving
+	// up with a JobTimeoutError. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// OnUpdate, if set, is called with the job's state after every check.
+	OnUpdate func(JobState)
+}
+
+// DefaultPollOptions is what PollJob falls back to for a zero-valued
+// InitialInterval or MaxInterval in the PollOptions it's given. A zero
+// MaxElapsedTime is not replaced: PollJob then polls with no time limit.
+func DefaultPollOptions() PollOptions {
+	return PollOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  5 * time.Minute,
+	}
+}
+
+// JobFailedError means Cloud Controller reported the job as failed.
+type JobFailedError struct {
+	JobGUID string
+	Errors  []JobErrorDetails
+}
+
+func (e JobFailedError) Error() string {
+	if len(e.Errors) > 0 {
+		return fmt.Sprintf("job %s failed: %s", e.JobGUID, e.Errors[0].Detail)
+	}
+	return fmt.Sprintf("job %s failed", e.JobGUID)
+}
+
+// JobTimeoutError means PollOptions.MaxElapsedTime elapsed before the job
+// reached a terminal state.
+type JobTimeoutError struct {
+	JobGUID string
+}
+
+func (e JobTimeoutError) Error() string {
+	return fmt.Sprintf("job %s did not complete before the timeout", e.JobGUID)
+}
+
+// JobCanceledError means the context passed to PollJob was done before the
+// job reached a terminal state.
+type JobCanceledError struct {
+	JobGUID string
+}
+
+func (e JobCanceledError) Error() string {
+	return fmt.Sprintf("polling for job %s was canceled", e.JobGUID)
+}
+
+// PollJob polls url with exponential backoff and jitter between checks
+// until the job completes, fails, ctx is done, or MaxElapsedTime elapses -
+// whichever happens first.
+func (client *Client) PollJob(ctx context.Context, url JobURL, opts PollOptions) (Warnings, error) {
+	return pollJob(ctx, opts, func() (Job, Warnings, error) {
+		return client.GetJob(url)
+	})
+}
+
+// pollJob holds PollJob's backoff/timeout/cancellation logic, fetching the
+// job through getJob instead of a *Client so job_test.go can drive it with
+// a fake and a manual clock instead of a real Cloud Controller connection.
+func pollJob(ctx context.Context, opts PollOptions, getJob func() (Job, Warnings, error)) (Warnings, error) {
+	defaults := DefaultPollOptions()
+	interval := opts.InitialInterval
+	if interval <= 0 {
+		interval = defaults.InitialInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaults.MaxInterval
+	}
+
+	var deadline <-chan time.Time
+	if opts.MaxElapsedTime > 0 {
+		timer := time.NewTimer(opts.MaxElapsedTime)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var allWarnings Warnings
+	for {
+		job, warnings, err := getJob()
+		allWarnings = append(allWarnings, warnings...)
+		if err != nil {
+			return allWarnings, err
+		}
+
+		if opts.OnUpdate != nil {
+			opts.OnUpdate(job.State)
+		}
+
+		switch job.State {
+		case JobStateComplete:
+			return allWarnings, nil
+		case JobStateFailed:
+			return allWarnings, JobFailedError{JobGUID: job.GUID, Errors: job.Errors}
+		}
+
+		select {
+		case <-ctx.Done():
+			return allWarnings, JobCanceledError{JobGUID: job.GUID}
+		case <-deadline:
+			return allWarnings, JobTimeoutError{JobGUID: job.GUID}
+		case <-time.After(jitter(interval)):
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// DeleteApplicationAndWait deletes the app with the given GUID and polls
+// the resulting job to completion, so callers don't have to reimplement a
+// polling loop around every JobURL-returning call. ctx bounds the poll the
+// same way it bounds PollJob - pass context.Background() if the caller has
+// nothing to cancel or time out on.
+func (client *Client) DeleteApplicationAndWait(ctx context.Context, appGUID string, opts PollOptions) (Warnings, error) {
+	jobURL, warnings, err := client.DeleteApplication(appGUID)
+	if err != nil {
+		return warnings, err
+	}
+
+	pollWarnings, err := client.PollJob(ctx, jobURL, opts)
+	return append(warnings, pollWarnings...), err
+}
+
+// UpdateSpaceApplyManifestAndWait applies rawManifest to the given space
+// and polls the resulting job to completion. ctx bounds the poll the same
+// way it bounds PollJob - pass context.Background() if the caller has
+// nothing to cancel or time out on.
+func (client *Client) UpdateSpaceApplyManifestAndWait(ctx context.Context, spaceGUID string, rawManifest []byte, opts PollOptions, query ...Query) (Warnings, error) {
+	jobURL, warnings, err := client.UpdateSpaceApplyManifest(spaceGUID, rawManifest, query...)
+	if err != nil {
+		return warnings, err
+	}
+
+	pollWarnings, err := client.PollJob(ctx, jobURL, opts)
+	return append(warnings, pollWarnings...), err
+}
@@ -0,0 +1,134 @@
+package ccv3
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// stubJobFetcher hands back the next entry in jobs on every call, repeating
+// the last one once they run out, and records how many times it was called
+// so tests can assert how many polls happened.
+type stubJobFetcher struct {
+	mu    sync.Mutex
+	jobs  []Job
+	calls int
+}
+
+func (s *stubJobFetcher) getJob() (Job, Warnings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.calls
+	if i >= len(s.jobs) {
+		i = len(s.jobs) - 1
+	}
+	s.calls++
+	return s.jobs[i], Warnings{"a warning"}, nil
+}
+
+func (s *stubJobFetcher) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+var _ = Describe("pollJob", func() {
+	var opts PollOptions
+
+	BeforeEach(func() {
+		opts = PollOptions{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+		}
+	})
+
+	When("the job completes", func() {
+		It("returns without error and reports every intermediate state", func() {
+			fetcher := &stubJobFetcher{jobs: []Job{
+				{GUID: "job-guid", State: JobStateProcessing},
+				{GUID: "job-guid", State: JobStateProcessing},
+				{GUID: "job-guid", State: JobStateComplete},
+			}}
+
+			var seenStates []JobState
+			opts.OnUpdate = func(state JobState) { seenStates = append(seenStates, state) }
+
+			warnings, err := pollJob(context.Background(), opts, fetcher.getJob)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(3))
+			Expect(seenStates).To(Equal([]JobState{JobStateProcessing, JobStateProcessing, JobStateComplete}))
+		})
+	})
+
+	When("the job fails", func() {
+		It("returns a JobFailedError", func() {
+			fetcher := &stubJobFetcher{jobs: []Job{
+				{GUID: "job-guid", State: JobStateFailed, Errors: []JobErrorDetails{{Detail: "boom"}}},
+			}}
+
+			_, err := pollJob(context.Background(), opts, fetcher.getJob)
+
+			Expect(err).To(Equal(JobFailedError{JobGUID: "job-guid", Errors: []JobErrorDetails{{Detail: "boom"}}}))
+		})
+	})
+
+	When("fetching the job returns an error", func() {
+		It("returns that error immediately", func() {
+			fetchErr := errors.New("network error")
+			_, err := pollJob(context.Background(), opts, func() (Job, Warnings, error) {
+				return Job{}, nil, fetchErr
+			})
+
+			Expect(err).To(Equal(fetchErr))
+		})
+	})
+
+	When("the context is canceled before the job reaches a terminal state", func() {
+		It("returns a JobCanceledError", func() {
+			fetcher := &stubJobFetcher{jobs: []Job{
+				{GUID: "job-guid", State: JobStateProcessing},
+			}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := pollJob(ctx, opts, fetcher.getJob)
+
+			Expect(err).To(Equal(JobCanceledError{JobGUID: "job-guid"}))
+		})
+	})
+
+	When("MaxElapsedTime elapses before the job reaches a terminal state", func() {
+		It("returns a JobTimeoutError", func() {
+			opts.MaxElapsedTime = time.Millisecond
+			opts.InitialInterval = time.Hour // long enough that the deadline always wins the select
+
+			fetcher := &stubJobFetcher{jobs: []Job{
+				{GUID: "job-guid", State: JobStateProcessing},
+			}}
+
+			_, err := pollJob(context.Background(), opts, fetcher.getJob)
+
+			Expect(err).To(Equal(JobTimeoutError{JobGUID: "job-guid"}))
+		})
+	})
+
+	When("OnUpdate is not set", func() {
+		It("still polls and completes normally", func() {
+			fetcher := &stubJobFetcher{jobs: []Job{
+				{GUID: "job-guid", State: JobStateComplete},
+			}}
+
+			_, err := pollJob(context.Background(), opts, fetcher.getJob)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fetcher.callCount()).To(Equal(1))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package ccv3
 
 import (
 	"bytes"
+	"strings"
 
 	"code.cloudfoundry.org/cli/api/cloudcontroller"
 	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3/internal"
@@ -10,6 +11,14 @@ import (
 // JobURL is the URL to a given Job.
 type JobURL string
 
+// guid extracts the job GUID from the tail of the URL, e.g.
+// "https://api.example.com/v3/jobs/abcd-1234" -> "abcd-1234", so it can be
+// used as a URI param on the job-polling request below.
+func (j JobURL) guid() string {
+	parts := strings.Split(string(j), "/")
+	return parts[len(parts)-1]
+}
+
 // DeleteApplication deletes the app with the given app GUID. Returns back a
 // resulting job URL to poll.
 func (client *Client) DeleteApplication(appGUID string) (JobURL, Warnings, error) {
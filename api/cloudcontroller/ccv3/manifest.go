@@ -0,0 +1,53 @@
+package ccv3
+
+import (
+	"gopkg.in/yaml.v2"
+
+	"code.cloudfoundry.org/cli/util/manifest"
+)
+
+// ToTyped converts m, a manifest.Manifest assembled from one or more
+// Sources, into the strongly-typed Manifest this package validates against
+// - by round-tripping it through YAML, since manifest.Application is kept
+// as a generic map precisely so unrecognized fields pass through
+// untouched. manifest.Manifest remains the right type for assembling and
+// composing a manifest (Append/Filter/Transform); Manifest is the right
+// type for checking the result is safe to send. ToTyped is the bridge
+// between them so a caller never has to choose one abstraction and lose
+// the other.
+func ToTyped(m manifest.Manifest) (Manifest, error) {
+	raw, err := m.Bytes()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var typed Manifest
+	if err := yaml.Unmarshal(raw, &typed); err != nil {
+		return Manifest{}, err
+	}
+
+	return typed, nil
+}
+
+// ApplySpaceManifest validates m - by converting it to a Manifest via
+// ToTyped - and, if it passes, marshals m and applies it to the given
+// space via UpdateSpaceApplyManifest, so callers that assembled their
+// manifest from a manifest.Manifest don't have to render the YAML or
+// validate it themselves first.
+func (client *Client) ApplySpaceManifest(spaceGUID string, m manifest.Manifest, query ...Query) (JobURL, Warnings, error) {
+	typed, err := ToTyped(m)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := typed.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	raw, err := m.Bytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return client.UpdateSpaceApplyManifest(spaceGUID, raw, query...)
+}
@@ -0,0 +1,37 @@
+package ccv3_test
+
+import (
+	. "code.cloudfoundry.org/cli/api/cloudcontroller/ccv3"
+	"code.cloudfoundry.org/cli/util/manifest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ToTyped", func() {
+	It("converts a manifest.Manifest into the equivalent typed Manifest", func() {
+		untyped, err := manifest.ManifestFrom(manifest.Slice{Applications: []manifest.Application{
+			{"name": "some-app", "health-check-type": "http", "health-check-http-endpoint": "/healthz"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		typed, err := ToTyped(untyped)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typed.Applications).To(HaveLen(1))
+		Expect(typed.Applications[0].Name).To(Equal("some-app"))
+		Expect(typed.Applications[0].HealthCheckHTTPEndpoint).To(Equal("/healthz"))
+	})
+
+	When("the untyped manifest would fail typed validation", func() {
+		It("carries the same violation through the conversion", func() {
+			untyped, err := manifest.ManifestFrom(manifest.Slice{Applications: []manifest.Application{
+				{"name": "some-app", "health-check-http-endpoint": "/healthz"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			typed, err := ToTyped(untyped)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typed.Validate()).To(MatchError(ContainSubstring("health-check-http-endpoint requires health-check-type: http")))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package ccv3
+
+import (
+	"bytes"
+	"errors"
+
+	"code.cloudfoundry.org/cli/api/cloudcontroller"
+	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3/internal"
+)
+
+// ManifestDiffOp is a single added, removed, or changed field the manifest
+// diff endpoint reports, in JSON Patch-like shape.
+type ManifestDiffOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Was   interface{} `json:"was,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ManifestDiff is what applying a manifest would change, per app, process,
+// and route - computed without actually applying anything.
+type ManifestDiff struct {
+	Diffs []ManifestDiffOp `json:"diff"`
+}
+
+// DiffApplicationManifest previews what applying rawManifest to the given
+// application would change.
+func (client *Client) DiffApplicationManifest(appGUID string, rawManifest []byte) (ManifestDiff, Warnings, error) {
+	return client.diffManifest(requestOptions{
+		RequestName: internal.PostApplicationDiffManifestRequest,
+		URIParams:   internal.Params{"app_guid": appGUID},
+		Body:        bytes.NewReader(rawManifest),
+	})
+}
+
+// DiffSpaceManifest previews what applying rawManifest to the given space
+// would change, across every app it describes.
+func (client *Client) DiffSpaceManifest(spaceGUID string, rawManifest []byte) (ManifestDiff, Warnings, error) {
+	return client.diffManifest(requestOptions{
+		RequestName: internal.PostSpaceDiffManifestRequest,
+		URIParams:   internal.Params{"space_guid": spaceGUID},
+		Body:        bytes.NewReader(rawManifest),
+	})
+}
+
+func (client *Client) diffManifest(options requestOptions) (ManifestDiff, Warnings, error) {
+	request, err := client.NewHTTPRequest(options)
+	if err != nil {
+		return ManifestDiff{}, nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-yaml")
+
+	var diff ManifestDiff
+	response := cloudcontroller.Response{DecodeJSONResponseInto: &diff}
+	err = client.Connection.Make(request, &response)
+
+	return diff, response.Warnings, err
+}
+
+// ErrManifestDiffRejected is returned by UpdateSpaceApplyManifestWithOptions
+// when ApplyOptions.RequireDiffApproval rejects the computed diff.
+var ErrManifestDiffRejected = errors.New("ccv3: manifest diff was rejected")
+
+// ApplyOptions controls UpdateSpaceApplyManifestWithOptions.
+type ApplyOptions struct {
+	// DryRun, if true, stops after computing the diff: the manifest is
+	// never applied.
+	DryRun bool
+
+	// RequireDiffApproval, if set, is called with the computed diff before
+	// the manifest is applied. If it returns false, the apply is aborted
+	// and ErrManifestDiffRejected is returned.
+	RequireDiffApproval func(ManifestDiff) bool
+}
+
+// UpdateSpaceApplyManifestWithOptions computes the diff applying
+// rawManifest to spaceGUID would produce, lets opts decide whether that's
+// acceptable, and only then (unless opts.DryRun) applies it - so tooling
+// can show an operator exactly what a manifest will do (memory bumps,
+// route additions, env var removals) before committing to the job.
+func (client *Client) UpdateSpaceApplyManifestWithOptions(spaceGUID string, rawManifest []byte, opts ApplyOptions, query ...Query) (JobURL, ManifestDiff, Warnings, error) {
+	return applyManifestWithOptions(
+		opts,
+		func() (ManifestDiff, Warnings, error) {
+			return client.DiffSpaceManifest(spaceGUID, rawManifest)
+		},
+		func() (JobURL, Warnings, error) {
+			return client.UpdateSpaceApplyManifest(spaceGUID, rawManifest, query...)
+		},
+	)
+}
+
+// applyManifestWithOptions holds UpdateSpaceApplyManifestWithOptions's
+// diff/approve/apply decision logic, taking the diff and apply steps as
+// closures instead of calling *Client directly so manifest_diff_test.go can
+// exercise it with fakes instead of a real Cloud Controller connection
+// (not available in this snapshot).
+func applyManifestWithOptions(opts ApplyOptions, diffManifest func() (ManifestDiff, Warnings, error), applyManifest func() (JobURL, Warnings, error)) (JobURL, ManifestDiff, Warnings, error) {
+	diff, warnings, err := diffManifest()
+	if err != nil {
+		return "", ManifestDiff{}, warnings, err
+	}
+
+	if opts.RequireDiffApproval != nil && !opts.RequireDiffApproval(diff) {
+		return "", diff, warnings, ErrManifestDiffRejected
+	}
+
+	if opts.DryRun {
+		return "", diff, warnings, nil
+	}
+
+	jobURL, applyWarnings, err := applyManifest()
+	return jobURL, diff, append(warnings, applyWarnings...), err
+}
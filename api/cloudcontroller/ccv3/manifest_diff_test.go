@@ -0,0 +1,109 @@
+package ccv3
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("applyManifestWithOptions", func() {
+	var (
+		computedDiff  ManifestDiff
+		diffWarnings  Warnings
+		diffErr       error
+		applyCalled   bool
+		applyJobURL   JobURL
+		applyWarnings Warnings
+		applyErr      error
+		diffManifest  func() (ManifestDiff, Warnings, error)
+		applyManifest func() (JobURL, Warnings, error)
+	)
+
+	BeforeEach(func() {
+		computedDiff = ManifestDiff{Diffs: []ManifestDiffOp{{Op: "replace", Path: "/applications/0/memory"}}}
+		diffWarnings = Warnings{"diff warning"}
+		diffErr = nil
+		applyCalled = false
+		applyJobURL = "some-job-url"
+		applyWarnings = Warnings{"apply warning"}
+		applyErr = nil
+
+		diffManifest = func() (ManifestDiff, Warnings, error) { return computedDiff, diffWarnings, diffErr }
+		applyManifest = func() (JobURL, Warnings, error) {
+			applyCalled = true
+			return applyJobURL, applyWarnings, applyErr
+		}
+	})
+
+	When("computing the diff fails", func() {
+		It("returns the error and never calls apply", func() {
+			diffErr = errors.New("boom")
+
+			_, _, warnings, err := applyManifestWithOptions(ApplyOptions{}, diffManifest, applyManifest)
+
+			Expect(err).To(Equal(diffErr))
+			Expect(warnings).To(Equal(diffWarnings))
+			Expect(applyCalled).To(BeFalse())
+		})
+	})
+
+	When("RequireDiffApproval rejects the diff", func() {
+		It("returns ErrManifestDiffRejected and never calls apply", func() {
+			var seen ManifestDiff
+			opts := ApplyOptions{
+				RequireDiffApproval: func(diff ManifestDiff) bool {
+					seen = diff
+					return false
+				},
+			}
+
+			jobURL, diff, warnings, err := applyManifestWithOptions(opts, diffManifest, applyManifest)
+
+			Expect(err).To(Equal(ErrManifestDiffRejected))
+			Expect(jobURL).To(BeEmpty())
+			Expect(diff).To(Equal(computedDiff))
+			Expect(warnings).To(Equal(diffWarnings))
+			Expect(seen).To(Equal(computedDiff))
+			Expect(applyCalled).To(BeFalse())
+		})
+	})
+
+	When("DryRun is set", func() {
+		It("returns the diff without ever calling apply", func() {
+			opts := ApplyOptions{DryRun: true}
+
+			jobURL, diff, warnings, err := applyManifestWithOptions(opts, diffManifest, applyManifest)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobURL).To(BeEmpty())
+			Expect(diff).To(Equal(computedDiff))
+			Expect(warnings).To(Equal(diffWarnings))
+			Expect(applyCalled).To(BeFalse())
+		})
+	})
+
+	When("the diff is approved and DryRun is false", func() {
+		It("applies the manifest and merges warnings from both steps", func() {
+			jobURL, diff, warnings, err := applyManifestWithOptions(ApplyOptions{}, diffManifest, applyManifest)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applyCalled).To(BeTrue())
+			Expect(jobURL).To(Equal(applyJobURL))
+			Expect(diff).To(Equal(computedDiff))
+			Expect(warnings).To(Equal(Warnings{"diff warning", "apply warning"}))
+		})
+	})
+
+	When("approved and apply itself fails", func() {
+		It("still returns the computed diff alongside the apply error", func() {
+			applyErr = errors.New("apply boom")
+
+			jobURL, diff, _, err := applyManifestWithOptions(ApplyOptions{}, diffManifest, applyManifest)
+
+			Expect(err).To(Equal(applyErr))
+			Expect(jobURL).To(Equal(applyJobURL))
+			Expect(diff).To(Equal(computedDiff))
+		})
+	})
+})
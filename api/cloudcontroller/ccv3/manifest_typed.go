@@ -0,0 +1,135 @@
+package ccv3
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ManifestSidecar is a single sidecar process attached to an app.
+type ManifestSidecar struct {
+	Name         string   `yaml:"name"`
+	ProcessTypes []string `yaml:"process_types"`
+	Command      string   `yaml:"command"`
+}
+
+// ManifestRoute is a single route entry under an application.
+type ManifestRoute struct {
+	Route string `yaml:"route"`
+}
+
+// ManifestProcess is a single process entry under an application.
+type ManifestProcess struct {
+	Type                         string `yaml:"type"`
+	HealthCheckType              string `yaml:"health-check-type,omitempty"`
+	HealthCheckHTTPEndpoint      string `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout int    `yaml:"health-check-invocation-timeout,omitempty"`
+}
+
+// ManifestApplication is a single application entry in a typed Manifest.
+type ManifestApplication struct {
+	Name      string                 `yaml:"name"`
+	Env       map[string]interface{} `yaml:"env,omitempty"`
+	Services  []string               `yaml:"services,omitempty"`
+	Routes    []ManifestRoute        `yaml:"routes,omitempty"`
+	Sidecars  []ManifestSidecar      `yaml:"sidecars,omitempty"`
+	Processes []ManifestProcess      `yaml:"processes,omitempty"`
+
+	HealthCheckType              string `yaml:"health-check-type,omitempty"`
+	HealthCheckHTTPEndpoint      string `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout int    `yaml:"health-check-invocation-timeout,omitempty"`
+}
+
+// Manifest is a strongly-typed application manifest, as an alternative to
+// passing around raw YAML bytes. Use UpdateApplicationApplyManifestTyped /
+// UpdateSpaceApplyManifestTyped to apply one; the []byte-based methods
+// remain for raw passthrough. A manifest assembled with util/manifest's
+// composable Source/Application builder isn't a Manifest itself - convert
+// it with ToTyped first to validate it the same way.
+type Manifest struct {
+	Applications []ManifestApplication `yaml:"applications"`
+}
+
+// ParseManifestStrict parses raw into a Manifest, rejecting any field it
+// doesn't recognize, and validates the result.
+func ParseManifestStrict(raw []byte) (Manifest, error) {
+	var m Manifest
+	if err := yaml.UnmarshalStrict(raw, &m); err != nil {
+		return Manifest{}, err
+	}
+
+	if err := m.Validate(); err != nil {
+		return Manifest{}, err
+	}
+
+	return m, nil
+}
+
+// Validate cross-checks constraints the API would otherwise only reject
+// after the apply-manifest job has already started.
+func (m Manifest) Validate() error {
+	for _, app := range m.Applications {
+		if err := app.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (app ManifestApplication) validate() error {
+	if err := validateHealthCheck(app.HealthCheckType, app.HealthCheckHTTPEndpoint); err != nil {
+		return fmt.Errorf("application %q: %w", app.Name, err)
+	}
+
+	for _, process := range app.Processes {
+		if err := validateHealthCheck(process.HealthCheckType, process.HealthCheckHTTPEndpoint); err != nil {
+			return fmt.Errorf("application %q process %q: %w", app.Name, process.Type, err)
+		}
+	}
+
+	return nil
+}
+
+func validateHealthCheck(healthCheckType string, httpEndpoint string) error {
+	if httpEndpoint != "" && healthCheckType != "http" {
+		return errors.New("health-check-http-endpoint requires health-check-type: http")
+	}
+	return nil
+}
+
+// Bytes marshals the Manifest to the application/x-yaml document the
+// apply-manifest endpoints expect.
+func (m Manifest) Bytes() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// UpdateApplicationApplyManifestTyped validates m and applies it to the
+// given application.
+func (client *Client) UpdateApplicationApplyManifestTyped(appGUID string, m Manifest) (JobURL, Warnings, error) {
+	if err := m.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	raw, err := m.Bytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return client.UpdateApplicationApplyManifest(appGUID, raw)
+}
+
+// UpdateSpaceApplyManifestTyped validates m and applies it to the given
+// space.
+func (client *Client) UpdateSpaceApplyManifestTyped(spaceGUID string, m Manifest, query ...Query) (JobURL, Warnings, error) {
+	if err := m.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	raw, err := m.Bytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return client.UpdateSpaceApplyManifest(spaceGUID, raw, query...)
+}
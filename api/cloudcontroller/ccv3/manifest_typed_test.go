@@ -0,0 +1,85 @@
+package ccv3_test
+
+import (
+	. "code.cloudfoundry.org/cli/api/cloudcontroller/ccv3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	Describe("Validate", func() {
+		When("an application's health-check-http-endpoint is set without health-check-type: http", func() {
+			It("returns an error", func() {
+				m := Manifest{
+					Applications: []ManifestApplication{
+						{Name: "some-app", HealthCheckHTTPEndpoint: "/healthz"},
+					},
+				}
+
+				Expect(m.Validate()).To(MatchError(ContainSubstring("health-check-http-endpoint requires health-check-type: http")))
+			})
+		})
+
+		When("a process's health-check-http-endpoint is set without health-check-type: http", func() {
+			It("returns an error naming the offending process", func() {
+				m := Manifest{
+					Applications: []ManifestApplication{
+						{
+							Name: "some-app",
+							Processes: []ManifestProcess{
+								{Type: "web", HealthCheckHTTPEndpoint: "/healthz"},
+							},
+						},
+					},
+				}
+
+				Expect(m.Validate()).To(MatchError(ContainSubstring(`process "web"`)))
+			})
+		})
+
+		When("health-check-type is http and an endpoint is set", func() {
+			It("does not return an error", func() {
+				m := Manifest{
+					Applications: []ManifestApplication{
+						{Name: "some-app", HealthCheckType: "http", HealthCheckHTTPEndpoint: "/healthz"},
+					},
+				}
+
+				Expect(m.Validate()).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ParseManifestStrict", func() {
+		It("rejects unknown top-level fields", func() {
+			_, err := ParseManifestStrict([]byte(`
+applications:
+- name: some-app
+not-a-real-field: true
+`))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("parses a well-formed manifest", func() {
+			m, err := ParseManifestStrict([]byte(`
+applications:
+- name: some-app
+  health-check-type: http
+  health-check-http-endpoint: /healthz
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Applications).To(HaveLen(1))
+			Expect(m.Applications[0].Name).To(Equal("some-app"))
+		})
+
+		It("rejects a manifest that fails cross-field validation", func() {
+			_, err := ParseManifestStrict([]byte(`
+applications:
+- name: some-app
+  health-check-http-endpoint: /healthz
+`))
+			Expect(err).To(MatchError(ContainSubstring("health-check-http-endpoint requires health-check-type: http")))
+		})
+	})
+})
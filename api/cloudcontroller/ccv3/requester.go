@@ -2,9 +2,12 @@ package ccv3
 
 import (
 	"bytes"
+	"encoding/json"
+	"math/rand"
+	"time"
+
 	"code.cloudfoundry.org/cli/api/cloudcontroller"
 	"code.cloudfoundry.org/cli/api/cloudcontroller/ccv3/internal"
-	"encoding/json"
 )
 
 type Requester interface {
@@ -12,16 +15,50 @@ type Requester interface {
 	MakeListRequest(client *Client, requestParams requestParams) ([]interface{}, Warnings, error)
 }
 
+// RequestFunc performs a single attempt at the request described by
+// requestParams and returns the raw cloudcontroller response. It's the unit
+// an Interceptor wraps.
+type RequestFunc func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error)
+
+// Interceptor wraps a RequestFunc with cross-cutting behavior - retries,
+// tracing, metrics, caching, canned-response replay in tests, and so on -
+// without the wrapped code needing to know it's there.
+type Interceptor func(next RequestFunc) RequestFunc
+
 type RealRequester struct {
+	interceptors []Interceptor
+}
 
+// NewRequester returns a RealRequester that sends every request through
+// interceptors, in the order given, before it reaches the wire.
+func NewRequester(interceptors ...Interceptor) *RealRequester {
+	return &RealRequester{interceptors: interceptors}
 }
 
-type requestParams struct {
-	RequestName  string
-	URIParams    internal.Params
-	Query        []Query
-	RequestBody  interface{}
-	ResponseBody interface{}
+// WithInterceptors returns a RealRequester that runs DefaultInterceptors()
+// first, followed by the given interceptors. Use this instead of
+// NewRequester when you want to add behavior on top of the CLI's normal
+// retry/tracing/metrics handling rather than replace it.
+func WithInterceptors(interceptors ...Interceptor) *RealRequester {
+	return NewRequester(append(DefaultInterceptors(), interceptors...)...)
+}
+
+// DefaultInterceptors returns the interceptor chain the CLI installs by
+// default: bounded retry with backoff on network errors and 502/503/504,
+// B3/request-id propagation, and a latency/status metrics hook.
+func DefaultInterceptors() []Interceptor {
+	return []Interceptor{
+		RetryInterceptor(DefaultRetryConfig()),
+		TraceHeaderInterceptor(),
+		MetricsInterceptor(NoopMetricsRecorder{}),
+	}
+}
+
+func (requester *RealRequester) chain(do RequestFunc) RequestFunc {
+	for i := len(requester.interceptors) - 1; i >= 0; i-- {
+		do = requester.interceptors[i](do)
+	}
+	return do
 }
 
 func (requester *RealRequester) MakeRequest(client *Client, requestParams requestParams) (JobURL, Warnings, error) {
@@ -45,13 +82,6 @@ func (requester *RealRequester) MakeRequest(client *Client, requestParams reques
 		return "", nil, err
 	}
 
-	response := cloudcontroller.Response{}
-	if requestParams.ResponseBody != nil {
-		response.DecodeJSONResponseInto = &requestParams.ResponseBody
-	}
-
-	err = client.Connection.Make(request, &response)
-
 	return requester.GetSingleResponse(client, requestParams, request)
 }
 
@@ -76,35 +106,201 @@ func (requester *RealRequester) MakeListRequest(client *Client, requestParams re
 		return nil, nil, err
 	}
 
-	response := cloudcontroller.Response{}
-	if requestParams.ResponseBody != nil {
-		response.DecodeJSONResponseInto = &requestParams.ResponseBody
-	}
-
-	err = client.Connection.Make(request, &response)
-
 	return requester.GetListResponse(client, requestParams, request)
 }
 
-func (requester *RealRequester) GetSingleResponse(client *Client, requestParams requestParams, request *cloudcontroller.Request) (JobURL, Warnings, error) {
-	response := cloudcontroller.Response{}
-	if requestParams.ResponseBody != nil {
-		response.DecodeJSONResponseInto = &requestParams.ResponseBody
-	}
+func (requester *RealRequester) GetSingleResponse(client *Client, params requestParams, request *cloudcontroller.Request) (JobURL, Warnings, error) {
+	do := requester.chain(func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+		response := cloudcontroller.Response{}
+		if requestParams.ResponseBody != nil {
+			response.DecodeJSONResponseInto = &requestParams.ResponseBody
+		}
 
-	err := client.Connection.Make(request, &response)
-	// unmarshals object of correct type into &response using unsafe.pointer directly to actor
+		err := client.Connection.Make(request, &response)
+		return response, err
+	})
+
+	response, err := do(client, params, request)
 
 	return JobURL(response.ResourceLocationURL), response.Warnings, err
 }
 
-func (requester *RealRequester) GetListResponse(client *Client, requestParams requestParams, request *cloudcontroller.Request) ([]interface{}, Warnings, error) {
+// GetListResponse walks every page of the list request through the
+// interceptor chain, so retry-on-5xx, trace-header injection, and metrics
+// apply to paginated calls (GetApplications, GetSpaces, etc.) the same way
+// they apply to single-resource ones. The chain wraps the whole paginated
+// fetch rather than each individual page fetch - client.paginate follows
+// "next" links internally and doesn't expose a seam for wrapping a single
+// page - so a retry re-runs pagination from the first page. That's safe
+// here because these are all GETs.
+func (requester *RealRequester) GetListResponse(client *Client, params requestParams, request *cloudcontroller.Request) ([]interface{}, Warnings, error) {
 	var fullResourceList []interface{}
 
-	warnings, err := client.paginate(request, requestParams.ResponseBody, func(item interface{}) error {
-		fullResourceList = append(fullResourceList, item)
-		return nil
+	do := requester.chain(func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+		fullResourceList = nil // reset so a retry doesn't double-append a previous attempt's pages
+
+		warnings, err := client.paginate(request, requestParams.ResponseBody, func(item interface{}) error {
+			fullResourceList = append(fullResourceList, item)
+			return nil
+		})
+
+		return cloudcontroller.Response{Warnings: warnings}, err
 	})
 
-	return fullResourceList, warnings, err
+	response, err := do(client, params, request)
+
+	return fullResourceList, response.Warnings, err
+}
+
+// RetryConfig controls RetryInterceptor's backoff behavior.
+type RetryConfig struct {
+	MaxRetries      int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:      2,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+	}
+}
+
+// RetryInterceptor retries a request with exponential backoff and jitter
+// when it fails with a network error or a 502/503/504 response, up to
+// config.MaxRetries times.
+func RetryInterceptor(config RetryConfig) Interceptor {
+	return func(next RequestFunc) RequestFunc {
+		return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			interval := config.InitialInterval
+
+			var response cloudcontroller.Response
+			var err error
+			for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+				if attempt > 0 {
+					resetRequestBody(request)
+				}
+
+				response, err = next(client, requestParams, request)
+				if err == nil || !isRetryable(err) || attempt == config.MaxRetries {
+					return response, err
+				}
+
+				time.Sleep(jitter(interval))
+				interval *= 2
+				if interval > config.MaxInterval {
+					interval = config.MaxInterval
+				}
+			}
+
+			return response, err
+		}
+	}
+}
+
+// resetRequestBody rewinds request's body before a retry. The previous
+// attempt's HTTP round trip already drained request.Body to EOF, so
+// resending it as-is would silently submit an empty body on every retried
+// POST/PATCH. GetBody is populated automatically by net/http whenever the
+// request was built from a bytes.Reader, bytes.Buffer, or strings.Reader -
+// which is how every request body in this client is constructed - and
+// returns a fresh, unread copy of it.
+func resetRequestBody(request *cloudcontroller.Request) {
+	if request == nil || request.GetBody == nil {
+		return
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return
+	}
+
+	request.Body = body
+}
+
+// retryableStatusError is implemented by cloudcontroller errors that carry
+// the HTTP status code of a failed response.
+type retryableStatusError interface {
+	StatusCode() int
+}
+
+func isRetryable(err error) bool {
+	if statusErr, ok := err.(retryableStatusError); ok {
+		switch statusErr.StatusCode() {
+		case 502, 503, 504:
+			return true
+		}
+		return false
+	}
+
+	// Anything else that isn't a recognized HTTP status error is treated as
+	// a network-level failure (connection reset, timeout, DNS, etc.) and is
+	// retried.
+	return true
+}
+
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+// TraceHeaderInterceptor stamps every outgoing request with a fresh
+// X-B3-TraceId and X-Request-Id so the request can be correlated across
+// CAPI, logs, and tracing backends.
+func TraceHeaderInterceptor() Interceptor {
+	return func(next RequestFunc) RequestFunc {
+		return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			id := newTraceID()
+			request.Header.Set("X-B3-TraceId", id)
+			request.Header.Set("X-Request-Id", id)
+
+			return next(client, requestParams, request)
+		}
+	}
+}
+
+func newTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	const hex = "0123456789abcdef"
+	out := make([]byte, 32)
+	for i, v := range b {
+		out[i*2] = hex[v>>4]
+		out[i*2+1] = hex[v&0x0f]
+	}
+	return string(out)
+}
+
+// MetricsRecorder receives one Record call per request, after it completes
+// or fails.
+type MetricsRecorder interface {
+	Record(requestName string, duration time.Duration, err error)
+}
+
+// NoopMetricsRecorder discards everything; it's the default so that
+// installing the interceptor chain has no effect on behavior unless a
+// caller supplies its own recorder.
+type NoopMetricsRecorder struct{}
+
+func (NoopMetricsRecorder) Record(requestName string, duration time.Duration, err error) {}
+
+// MetricsInterceptor records how long each request took and whether it
+// succeeded, tagged by internal.RequestName.
+func MetricsInterceptor(recorder MetricsRecorder) Interceptor {
+	return func(next RequestFunc) RequestFunc {
+		return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			start := time.Now()
+			response, err := next(client, requestParams, request)
+			recorder.Record(requestParams.RequestName, time.Since(start), err)
+			return response, err
+		}
+	}
+}
+
+type requestParams struct {
+	RequestName  string
+	URIParams    internal.Params
+	Query        []Query
+	RequestBody  interface{}
+	ResponseBody interface{}
 }
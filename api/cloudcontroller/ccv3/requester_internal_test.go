@@ -0,0 +1,170 @@
+package ccv3
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/cli/api/cloudcontroller"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeStatusError lets the retry tests drive isRetryable without depending
+// on a real cloudcontroller error type.
+type fakeStatusError struct{ status int }
+
+func (e fakeStatusError) Error() string {
+	return fmt.Sprintf("request failed with status %d", e.status)
+}
+
+func (e fakeStatusError) StatusCode() int { return e.status }
+
+var _ = Describe("RealRequester.chain", func() {
+	It("runs interceptors in the order given, outermost first", func() {
+		var calls []string
+
+		record := func(name string) Interceptor {
+			return func(next RequestFunc) RequestFunc {
+				return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+					calls = append(calls, name+":before")
+					response, err := next(client, requestParams, request)
+					calls = append(calls, name+":after")
+					return response, err
+				}
+			}
+		}
+
+		requester := NewRequester(record("outer"), record("inner"))
+		do := requester.chain(func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			calls = append(calls, "do")
+			return cloudcontroller.Response{}, nil
+		})
+
+		_, err := do(nil, requestParams{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([]string{"outer:before", "inner:before", "do", "inner:after", "outer:after"}))
+	})
+})
+
+var _ = Describe("RetryInterceptor", func() {
+	var config RetryConfig
+
+	BeforeEach(func() {
+		config = RetryConfig{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	})
+
+	attemptCountingNext := func(errs []error) (RequestFunc, *int) {
+		attempt := 0
+		return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			err := errs[attempt]
+			attempt++
+			return cloudcontroller.Response{}, err
+		}, &attempt
+	}
+
+	When("the request fails with a 503 and then succeeds", func() {
+		It("retries until it succeeds", func() {
+			next, attempts := attemptCountingNext([]error{fakeStatusError{503}, nil})
+			wrapped := RetryInterceptor(config)(next)
+
+			_, err := wrapped(nil, requestParams{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*attempts).To(Equal(2))
+		})
+	})
+
+	When("the request keeps failing with a 502 past MaxRetries", func() {
+		It("gives up and returns the last error", func() {
+			next, attempts := attemptCountingNext([]error{fakeStatusError{502}, fakeStatusError{502}, fakeStatusError{502}})
+			wrapped := RetryInterceptor(config)(next)
+
+			_, err := wrapped(nil, requestParams{}, nil)
+			Expect(err).To(Equal(fakeStatusError{502}))
+			Expect(*attempts).To(Equal(3))
+		})
+	})
+
+	When("the request fails with a 400", func() {
+		It("does not retry a non-retryable status", func() {
+			next, attempts := attemptCountingNext([]error{fakeStatusError{400}})
+			wrapped := RetryInterceptor(config)(next)
+
+			_, err := wrapped(nil, requestParams{}, nil)
+			Expect(err).To(Equal(fakeStatusError{400}))
+			Expect(*attempts).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("RetryInterceptor and request bodies", func() {
+	It("rebuilds the body before every retry instead of resending a drained reader", func() {
+		httpRequest, err := http.NewRequest(http.MethodPost, "https://example.com", bytes.NewReader([]byte(`{"name":"original"}`)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpRequest.GetBody).NotTo(BeNil(), "http.NewRequest should populate GetBody for a bytes.Reader body")
+
+		request := &cloudcontroller.Request{Request: httpRequest}
+
+		var bodiesSeen []string
+		attempt := 0
+		next := func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			raw, readErr := ioutil.ReadAll(request.Body)
+			Expect(readErr).NotTo(HaveOccurred())
+			bodiesSeen = append(bodiesSeen, string(raw))
+
+			attempt++
+			if attempt < 3 {
+				return cloudcontroller.Response{}, fakeStatusError{503}
+			}
+			return cloudcontroller.Response{}, nil
+		}
+
+		config := RetryConfig{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+		wrapped := RetryInterceptor(config)(next)
+
+		_, err = wrapped(nil, requestParams{}, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodiesSeen).To(Equal([]string{`{"name":"original"}`, `{"name":"original"}`, `{"name":"original"}`}))
+	})
+
+	When("request is nil or carries no GetBody", func() {
+		It("is a no-op instead of panicking", func() {
+			Expect(func() { resetRequestBody(nil) }).NotTo(Panic())
+
+			httpRequest, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			Expect(err).NotTo(HaveOccurred())
+			request := &cloudcontroller.Request{Request: httpRequest}
+			Expect(func() { resetRequestBody(request) }).NotTo(Panic())
+		})
+	})
+})
+
+var _ = Describe("MakeListRequest routing", func() {
+	It("wraps the request func built by requester.chain, which GetListResponse uses, with the installed interceptors", func() {
+		var sawListRequest bool
+
+		spy := Interceptor(func(next RequestFunc) RequestFunc {
+			return func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+				sawListRequest = true
+				return next(client, requestParams, request)
+			}
+		})
+
+		requester := NewRequester(spy)
+		do := requester.chain(func(client *Client, requestParams requestParams, request *cloudcontroller.Request) (cloudcontroller.Response, error) {
+			return cloudcontroller.Response{}, nil
+		})
+
+		// GetListResponse's own paginate call can't be driven here without a
+		// real cloudcontroller.Connection, which isn't available in this
+		// tree, so this drives requester.chain directly - the same call
+		// GetListResponse builds its request func with - rather than
+		// GetListResponse itself.
+		_, err := do(nil, requestParams{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sawListRequest).To(BeTrue())
+	})
+})
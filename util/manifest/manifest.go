@@ -0,0 +1,92 @@
+// Package manifest lets callers assemble the raw YAML that
+// ccv3.Client.UpdateApplicationApplyManifest and
+// ccv3.Client.UpdateSpaceApplyManifest send to Cloud Controller from more
+// than one source - a file, a directory tree, a URL, an io.Reader, or an
+// in-memory slice - and to filter or transform the result before it's sent.
+package manifest
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+// Application is a single app's manifest properties. It's kept as a
+// generic map, rather than a struct with known fields, so that manifest
+// properties this package doesn't know about round-trip through
+// Append/Filter/Transform untouched.
+type Application map[string]interface{}
+
+// Manifest is an immutable bag of Applications assembled from one or more
+// Sources. Append, Filter, and Transform all return a new Manifest rather
+// than mutating the receiver, so a Manifest can be shared and composed
+// freely.
+type Manifest struct {
+	applications []Application
+}
+
+// ManifestFrom parses source and wraps the result in a Manifest.
+func ManifestFrom(source Source) (Manifest, error) {
+	apps, err := source.Parse()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Manifest{applications: apps}, nil
+}
+
+// Applications returns the manifest's applications. The returned slice is a
+// copy; mutating it does not affect the Manifest.
+func (m Manifest) Applications() []Application {
+	return append([]Application(nil), m.applications...)
+}
+
+// Append returns a new Manifest containing this Manifest's applications
+// followed by other's.
+func (m Manifest) Append(other Manifest) Manifest {
+	combined := make([]Application, 0, len(m.applications)+len(other.applications))
+	combined = append(combined, m.applications...)
+	combined = append(combined, other.applications...)
+
+	return Manifest{applications: combined}
+}
+
+// Filter returns a new Manifest containing only the applications for which
+// pred returns true.
+func (m Manifest) Filter(pred func(Application) bool) Manifest {
+	var filtered []Application
+	for _, app := range m.applications {
+		if pred(app) {
+			filtered = append(filtered, app)
+		}
+	}
+
+	return Manifest{applications: filtered}
+}
+
+// Transform returns a new Manifest with transform applied to every
+// application, in order. If transform returns an error for any
+// application, Transform stops and returns that error.
+func (m Manifest) Transform(transform func(Application) (Application, error)) (Manifest, error) {
+	transformed := make([]Application, 0, len(m.applications))
+	for _, app := range m.applications {
+		next, err := transform(app)
+		if err != nil {
+			return Manifest{}, err
+		}
+		transformed = append(transformed, next)
+	}
+
+	return Manifest{applications: transformed}, nil
+}
+
+// Bytes marshals the Manifest to the application/x-yaml document
+// ccv3.Client.UpdateApplicationApplyManifest and
+// ccv3.Client.UpdateSpaceApplyManifest expect.
+func (m Manifest) Bytes() ([]byte, error) {
+	document := struct {
+		Applications []Application `yaml:"applications"`
+	}{
+		Applications: m.applications,
+	}
+
+	return yaml.Marshal(document)
+}
@@ -0,0 +1,139 @@
+package manifest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "code.cloudfoundry.org/cli/util/manifest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	Describe("merging multi-file manifests", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "manifest-recursive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "a.yml"), []byte(`
+applications:
+- name: app-a
+`), 0600)).To(Succeed())
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "b.yaml"), []byte(`
+applications:
+- name: app-b
+`), 0600)).To(Succeed())
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not a manifest"), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("combines every manifest file in the directory and skips non-manifest files", func() {
+			m, err := ManifestFrom(Recursive{Dir: dir})
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, app := range m.Applications() {
+				names = append(names, app["name"].(string))
+			}
+			Expect(names).To(ConsistOf("app-a", "app-b"))
+		})
+
+		It("Append combines two already-parsed manifests without mutating either", func() {
+			first, err := ManifestFrom(Slice{Applications: []Application{{"name": "app-a"}}})
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := ManifestFrom(Slice{Applications: []Application{{"name": "app-b"}}})
+			Expect(err).NotTo(HaveOccurred())
+
+			combined := first.Append(second)
+
+			Expect(combined.Applications()).To(Equal([]Application{{"name": "app-a"}, {"name": "app-b"}}))
+			Expect(first.Applications()).To(Equal([]Application{{"name": "app-a"}}))
+			Expect(second.Applications()).To(Equal([]Application{{"name": "app-b"}}))
+		})
+	})
+
+	Describe("Filter", func() {
+		It("keeps only the applications the predicate accepts", func() {
+			m, err := ManifestFrom(Slice{Applications: []Application{
+				{"name": "keep-me"},
+				{"name": "drop-me"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			filtered := m.Filter(func(app Application) bool {
+				return app["name"] == "keep-me"
+			})
+
+			Expect(filtered.Applications()).To(Equal([]Application{{"name": "keep-me"}}))
+		})
+	})
+
+	Describe("Transform", func() {
+		It("applies the transform to every application in order", func() {
+			m, err := ManifestFrom(Slice{Applications: []Application{
+				{"name": "app-a", "memory": "256M"},
+				{"name": "app-b", "memory": "256M"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			var seenInOrder []string
+			transformed, err := m.Transform(func(app Application) (Application, error) {
+				seenInOrder = append(seenInOrder, app["name"].(string))
+
+				next := Application{}
+				for k, v := range app {
+					next[k] = v
+				}
+				next["memory"] = "512M"
+				return next, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(seenInOrder).To(Equal([]string{"app-a", "app-b"}))
+			Expect(transformed.Applications()).To(Equal([]Application{
+				{"name": "app-a", "memory": "512M"},
+				{"name": "app-b", "memory": "512M"},
+			}))
+		})
+
+		When("a transform returns an error", func() {
+			It("stops and returns the error instead of a partial Manifest", func() {
+				m, err := ManifestFrom(Slice{Applications: []Application{{"name": "app-a"}}})
+				Expect(err).NotTo(HaveOccurred())
+
+				boom := ErrTest("boom")
+				_, err = m.Transform(func(app Application) (Application, error) {
+					return nil, boom
+				})
+				Expect(err).To(MatchError(boom))
+			})
+		})
+	})
+
+	Describe("Bytes", func() {
+		It("marshals the applications under an `applications` key", func() {
+			m, err := ManifestFrom(Slice{Applications: []Application{{"name": "app-a"}}})
+			Expect(err).NotTo(HaveOccurred())
+
+			raw, err := m.Bytes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(raw)).To(ContainSubstring("applications:"))
+			Expect(string(raw)).To(ContainSubstring("name: app-a"))
+		})
+	})
+})
+
+type ErrTest string
+
+func (e ErrTest) Error() string { return string(e) }
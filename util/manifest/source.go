@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Source produces the applications that make up a Manifest.
+type Source interface {
+	Parse() ([]Application, error)
+}
+
+// Reader parses a manifest document read from an arbitrary io.Reader.
+type Reader struct {
+	Reader io.Reader
+}
+
+func (r Reader) Parse() ([]Application, error) {
+	raw, err := ioutil.ReadAll(r.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Applications []Application `yaml:"applications"`
+	}
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+
+	return document.Applications, nil
+}
+
+// Path parses a single manifest file on disk.
+type Path struct {
+	FilePath string
+}
+
+func (p Path) Parse() ([]Application, error) {
+	file, err := os.Open(p.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Reader{Reader: file}.Parse()
+}
+
+// Recursive walks Dir and parses every *.yml/*.yaml file it finds, in
+// lexical order, concatenating their applications.
+type Recursive struct {
+	Dir string
+}
+
+func (r Recursive) Parse() ([]Application, error) {
+	var applications []Application
+
+	err := filepath.Walk(r.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yml", ".yaml":
+		default:
+			return nil
+		}
+
+		fileApps, err := (Path{FilePath: path}).Parse()
+		if err != nil {
+			return fmt.Errorf("parsing manifest %s: %w", path, err)
+		}
+		applications = append(applications, fileApps...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+// HTTP fetches a manifest document over HTTP(S) and parses it. Client
+// defaults to http.DefaultClient when nil.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+}
+
+func (h HTTP) Parse() ([]Application, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(h.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("manifest: unexpected status %d fetching %s", resp.StatusCode, h.URL)
+	}
+
+	return Reader{Reader: resp.Body}.Parse()
+}
+
+// Slice wraps an in-memory slice of already-parsed applications, e.g. ones
+// built up programmatically by a review-app tool.
+type Slice struct {
+	Applications []Application
+}
+
+func (s Slice) Parse() ([]Application, error) {
+	return s.Applications, nil
+}